@@ -0,0 +1,142 @@
+// Package browser opens URLs in a chosen web browser.
+package browser
+
+import (
+	"fmt"
+	neturl "net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+type app struct {
+	macOS string
+	linux string
+}
+
+var browsers = map[string]app{
+	"safari":  {macOS: "Safari"},
+	"arc":     {macOS: "Arc"},
+	"chrome":  {macOS: "Google Chrome", linux: "google-chrome"},
+	"firefox": {macOS: "Firefox", linux: "firefox"},
+	"brave":   {macOS: "Brave Browser", linux: "brave-browser"},
+	"edge":    {macOS: "Microsoft Edge", linux: "microsoft-edge"},
+}
+
+// Names returns the browser names Open accepts, besides "default".
+func Names() []string {
+	names := make([]string, 0, len(browsers))
+	for name := range browsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens url in the named browser. An empty name falls back to the
+// BROWSER environment variable and then the system default. Names that
+// aren't built in are treated like $BROWSER: a colon-separated list of
+// commands, each with optional arguments and a %s placeholder for the URL.
+func Open(url, name string) error {
+	if err := checkURL(url); err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv("BROWSER"))
+	}
+	if name == "" || strings.EqualFold(name, "default") {
+		return run(defaultCommand(url))
+	}
+
+	b, ok := browsers[strings.ToLower(name)]
+	if !ok {
+		return startCommandList(name, url)
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return run(exec.Command("open", "-a", b.macOS, url))
+	case "linux":
+		if b.linux == "" {
+			return fmt.Errorf("%s is not available on linux", name)
+		}
+		return start(exec.Command(b.linux, url))
+	}
+	return fmt.Errorf("choosing a browser is not supported on %s", runtime.GOOS)
+}
+
+// checkURL rejects anything but absolute http and https URLs. The URL is
+// passed to open, xdg-open and browsers as a positional argument, so a value
+// such as -foo from the clipboard would otherwise be read as a flag.
+func checkURL(raw string) error {
+	u, err := neturl.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("refusing to open %q: not an absolute http or https URL", raw)
+	}
+	return nil
+}
+
+// startCommandList starts the first command in a $BROWSER-style list that
+// is found in PATH. The URL replaces %s in its arguments, or is appended
+// when there is no placeholder.
+func startCommandList(list, url string) error {
+	for _, entry := range strings.Split(list, ":") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+		path, err := exec.LookPath(fields[0])
+		if err != nil {
+			continue
+		}
+		args := make([]string, 0, len(fields))
+		substituted := false
+		for _, arg := range fields[1:] {
+			if strings.Contains(arg, "%s") {
+				arg = strings.ReplaceAll(arg, "%s", url)
+				substituted = true
+			}
+			args = append(args, arg)
+		}
+		if !substituted {
+			args = append(args, url)
+		}
+		return start(exec.Command(path, args...))
+	}
+	return fmt.Errorf("unknown browser %q (available: %s)", list, strings.Join(Names(), ", "))
+}
+
+func defaultCommand(url string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url)
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	}
+	return exec.Command("xdg-open", url)
+}
+
+// run waits for short-lived openers such as open and xdg-open, which hand
+// the URL to the browser and exit.
+func run(cmd *exec.Cmd) error {
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("open %s: %w: %s", cmd.Args[len(cmd.Args)-1], err, msg)
+		}
+		return fmt.Errorf("open %s: %w", cmd.Args[len(cmd.Args)-1], err)
+	}
+	return nil
+}
+
+// start launches a browser executable without waiting for it. Run directly,
+// a browser stays in the foreground until the user closes it.
+func start(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("open %s: %w", cmd.Args[len(cmd.Args)-1], err)
+	}
+	return cmd.Process.Release()
+}
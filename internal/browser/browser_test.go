@@ -0,0 +1,152 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScript creates an executable shell script standing in for a browser.
+func writeScript(t *testing.T, name, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on windows")
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestOpenKeepsBrowserCommandCase(t *testing.T) {
+	path := writeScript(t, "My-Browser", "exit 0")
+	t.Setenv("BROWSER", "  "+path+"  ")
+
+	if err := Open("https://example.com", ""); err != nil {
+		t.Fatalf("Open with BROWSER=%q: %v", path, err)
+	}
+}
+
+func TestOpenDoesNotWaitForBrowser(t *testing.T) {
+	path := writeScript(t, "browser", "sleep 10")
+	t.Setenv("BROWSER", path)
+
+	begin := time.Now()
+	if err := Open("https://example.com", ""); err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 5*time.Second {
+		t.Errorf("Open blocked for %v waiting for the browser to exit", elapsed)
+	}
+}
+
+// recordingScript returns a stub browser that writes its arguments, one per
+// line, to the returned file.
+func recordingScript(t *testing.T, name string) (script, argsFile string) {
+	t.Helper()
+	argsFile = filepath.Join(t.TempDir(), "args")
+	body := `tmp="` + argsFile + `.tmp"; for a in "$@"; do printf '%s\n' "$a" >> "$tmp"; done; mv "$tmp" "` + argsFile + `"`
+	return writeScript(t, name, body), argsFile
+}
+
+// waitForArgs waits for a recording script started in the background.
+func waitForArgs(t *testing.T, argsFile string) []string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(argsFile); err == nil {
+			return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("browser stub never ran")
+	return nil
+}
+
+func TestOpenBrowserEnvForms(t *testing.T) {
+	const target = "https://example.com/a?b=1"
+	tests := []struct {
+		name    string
+		browser func(script string) string
+		want    []string
+	}{
+		{
+			name:    "command with arguments",
+			browser: func(script string) string { return script + " --new-window" },
+			want:    []string{"--new-window", target},
+		},
+		{
+			name:    "colon-separated list",
+			browser: func(script string) string { return "/nonexistent/browser:" + script },
+			want:    []string{target},
+		},
+		{
+			name:    "list with missing name and arguments",
+			browser: func(script string) string { return "no-such-browser-binary --x:" + script + " -a" },
+			want:    []string{"-a", target},
+		},
+		{
+			name:    "percent-s placeholder",
+			browser: func(script string) string { return script + " --url=%s --private" },
+			want:    []string{"--url=" + target, "--private"},
+		},
+		{
+			name:    "empty entries",
+			browser: func(script string) string { return "::" + script + ":" },
+			want:    []string{target},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, argsFile := recordingScript(t, "browser")
+			t.Setenv("BROWSER", tt.browser(script))
+
+			if err := Open(target, ""); err != nil {
+				t.Fatalf("Open error: %v", err)
+			}
+			if got := waitForArgs(t, argsFile); !slices.Equal(got, tt.want) {
+				t.Errorf("browser args = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenUnknownBrowser(t *testing.T) {
+	t.Setenv("BROWSER", "")
+	for _, name := range []string{"no-such-browser-binary", "no-such-browser-a:no-such-browser-b", "no-such-browser-binary --new-window"} {
+		if err := Open("https://example.com", name); err == nil {
+			t.Errorf("Open with %q succeeded, want error", name)
+		}
+	}
+}
+
+func TestOpenRejectsNonHTTPURLs(t *testing.T) {
+	script, argsFile := recordingScript(t, "browser")
+	t.Setenv("BROWSER", script)
+
+	for _, target := range []string{
+		"",
+		"-foo",
+		"--new-window",
+		"-https://example.com",
+		"example.com",
+		"/etc/passwd",
+		"file:///etc/passwd",
+		"javascript:alert(1)",
+		"https://",
+		"ftp://example.com",
+	} {
+		if err := Open(target, ""); err == nil {
+			t.Errorf("Open(%q) succeeded, want error", target)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, err := os.Stat(argsFile); err == nil {
+		t.Error("browser ran for a rejected URL")
+	}
+}
@@ -0,0 +1,91 @@
+// Package clipboard reads and writes the system clipboard through the
+// platform's clipboard tools: pbcopy/pbpaste on macOS, wl-clipboard on
+// Wayland, xclip or xsel on X11, and PowerShell on Windows.
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when no supported clipboard tool is installed.
+var ErrUnavailable = errors.New("no clipboard tool found (install wl-clipboard, xclip or xsel)")
+
+// tool is one clipboard backend: the commands that print the clipboard and
+// that replace it with standard input.
+type tool struct {
+	read  []string
+	write []string
+}
+
+// candidates lists the backends for goos in order of preference.
+func candidates(goos string, wayland bool) []tool {
+	switch goos {
+	case "darwin":
+		return []tool{{read: []string{"pbpaste"}, write: []string{"pbcopy"}}}
+	case "windows":
+		return []tool{{
+			read:  []string{"powershell", "-NoProfile", "-Command", "Get-Clipboard -Raw"},
+			write: []string{"powershell", "-NoProfile", "-Command", "[Console]::In.ReadToEnd() | Set-Clipboard"},
+		}}
+	}
+	x11 := []tool{
+		{read: []string{"xclip", "-selection", "clipboard", "-o"}, write: []string{"xclip", "-selection", "clipboard", "-i"}},
+		{read: []string{"xsel", "--clipboard", "--output"}, write: []string{"xsel", "--clipboard", "--input"}},
+	}
+	if wayland {
+		wl := tool{read: []string{"wl-paste", "--no-newline"}, write: []string{"wl-copy"}}
+		return append([]tool{wl}, x11...)
+	}
+	return x11
+}
+
+// find returns the first backend whose binary is in PATH.
+func find() (tool, error) {
+	for _, t := range candidates(runtime.GOOS, os.Getenv("WAYLAND_DISPLAY") != "") {
+		if _, err := exec.LookPath(t.read[0]); err == nil {
+			return t, nil
+		}
+	}
+	return tool{}, ErrUnavailable
+}
+
+// ReadText returns the clipboard contents as text.
+func ReadText() (string, error) {
+	t, err := find()
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.Command(t.read[0], t.read[1:]...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+				return "", fmt.Errorf("read clipboard with %s: %w: %s", t.read[0], err, msg)
+			}
+		}
+		return "", fmt.Errorf("read clipboard with %s: %w", t.read[0], err)
+	}
+	return string(out), nil
+}
+
+// WriteText replaces the clipboard contents with text.
+func WriteText(text string) error {
+	t, err := find()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(t.write[0], t.write[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	// xclip and wl-copy fork a child that keeps serving the selection. Any
+	// output pipe would be held open by that child and block Run, so
+	// stdout and stderr are left unconnected.
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("write clipboard with %s: %w", t.write[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		goos    string
+		wayland bool
+		want    []string
+	}{
+		{"darwin", false, []string{"pbpaste"}},
+		{"darwin", true, []string{"pbpaste"}},
+		{"windows", false, []string{"powershell"}},
+		{"linux", false, []string{"xclip", "xsel"}},
+		{"linux", true, []string{"wl-paste", "xclip", "xsel"}},
+		{"freebsd", false, []string{"xclip", "xsel"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range candidates(tt.goos, tt.wayland) {
+			got = append(got, c.read[0])
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("candidates(%q, %v) = %q, want %q", tt.goos, tt.wayland, got, tt.want)
+		}
+	}
+}
+
+// stubTools installs fake clipboard tools in an otherwise empty PATH. Each
+// records its arguments to <name>.args, saves stdin to <name>.in, and
+// prints "<name> contents".
+func stubTools(t *testing.T, names ...string) string {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("stub clipboard tools are only wired up on linux")
+	}
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not found")
+	}
+	dir := t.TempDir()
+	for _, name := range names {
+		base := filepath.Join(dir, name)
+		script := "#!/bin/sh\n" +
+			"for a in \"$@\"; do printf '%s\\n' \"$a\" >> " + base + ".args; done\n" +
+			"case \"$*\" in *-o*|*--output*|*--no-newline*) printf '" + name + " contents' ;; *) " + cat + " > " + base + ".in ;; esac\n"
+		if err := os.WriteFile(base, []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+	return dir
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("tool did not run: %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestReadText(t *testing.T) {
+	tests := []struct {
+		name     string
+		tools    []string
+		wayland  string
+		want     string
+		wantArgs []string
+	}{
+		{"wayland", []string{"wl-paste", "wl-copy", "xclip"}, "wayland-0", "wl-paste contents", []string{"--no-newline"}},
+		{"x11 prefers xclip", []string{"wl-paste", "xclip", "xsel"}, "", "xclip contents", []string{"-selection", "clipboard", "-o"}},
+		{"wayland without wl-clipboard", []string{"xclip"}, "wayland-0", "xclip contents", []string{"-selection", "clipboard", "-o"}},
+		{"xsel fallback", []string{"xsel"}, "", "xsel contents", []string{"--clipboard", "--output"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := stubTools(t, tt.tools...)
+			t.Setenv("WAYLAND_DISPLAY", tt.wayland)
+
+			got, err := ReadText()
+			if err != nil {
+				t.Fatalf("ReadText error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ReadText = %q, want %q", got, tt.want)
+			}
+			tool := strings.Fields(tt.want)[0]
+			if args := readLines(t, filepath.Join(dir, tool+".args")); !slices.Equal(args, tt.wantArgs) {
+				t.Errorf("%s args = %q, want %q", tool, args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	tests := []struct {
+		name     string
+		tools    []string
+		wayland  string
+		tool     string
+		wantArgs []string
+	}{
+		{"wayland", []string{"wl-paste", "wl-copy"}, "wayland-0", "wl-copy", nil},
+		{"xclip", []string{"xclip", "xsel"}, "", "xclip", []string{"-selection", "clipboard", "-i"}},
+		{"xsel", []string{"xsel"}, "", "xsel", []string{"--clipboard", "--input"}},
+	}
+	const text = "-first line\nsecond line\n"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := stubTools(t, tt.tools...)
+			t.Setenv("WAYLAND_DISPLAY", tt.wayland)
+
+			if err := WriteText(text); err != nil {
+				t.Fatalf("WriteText error: %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, tt.tool+".in"))
+			if err != nil {
+				t.Fatalf("%s did not receive input: %v", tt.tool, err)
+			}
+			if string(data) != text {
+				t.Errorf("%s stdin = %q, want %q", tt.tool, data, text)
+			}
+			if tt.wantArgs != nil {
+				if args := readLines(t, filepath.Join(dir, tt.tool+".args")); !slices.Equal(args, tt.wantArgs) {
+					t.Errorf("%s args = %q, want %q", tt.tool, args, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	stubTools(t)
+	t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+
+	if _, err := ReadText(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("ReadText error = %v, want ErrUnavailable", err)
+	}
+	if err := WriteText("x"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("WriteText error = %v, want ErrUnavailable", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package editor opens files and directories in a chosen code editor.
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+type app struct {
+	bin      string
+	macOS    string
+	terminal bool
+}
+
+var editors = map[string]app{
+	"cursor": {bin: "cursor", macOS: "Cursor"},
+	"code":   {bin: "code", macOS: "Visual Studio Code"},
+	"zed":    {bin: "zed", macOS: "Zed"},
+	"idea":   {bin: "idea", macOS: "IntelliJ IDEA"},
+	"nvim":   {bin: "nvim", terminal: true},
+}
+
+// ErrNoEditor is returned when no editor is given and none is configured.
+var ErrNoEditor = errors.New("no editor configured (set VISUAL or EDITOR)")
+
+// Names returns the editor names Open accepts.
+func Names() []string {
+	names := make([]string, 0, len(editors))
+	for name := range editors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Detect returns the registry name matching $VISUAL or $EDITOR, or "" when
+// neither names a known editor.
+func Detect() string {
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		fields := strings.Fields(os.Getenv(key))
+		if len(fields) == 0 {
+			continue
+		}
+		name := filepath.Base(fields[0])
+		if _, ok := editors[name]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// Open opens path in the named editor. An empty name runs the command in
+// $VISUAL or $EDITOR instead. GUI editors are started in the background;
+// terminal editors and unknown $EDITOR commands take over the terminal
+// until they exit.
+func Open(path, name string) error {
+	// An absolute path can't be mistaken for a flag by the editor.
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", path, err)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return openFromEnv(abs)
+	}
+
+	e, ok := editors[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown editor %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	if bin, err := exec.LookPath(e.bin); err == nil {
+		if e.terminal {
+			return runAttached(exec.Command(bin, abs))
+		}
+		return start(exec.Command(bin, abs))
+	}
+	if runtime.GOOS == "darwin" && e.macOS != "" {
+		return run(exec.Command("open", "-a", e.macOS, abs))
+	}
+	return fmt.Errorf("%s not found in PATH", e.bin)
+}
+
+// openFromEnv runs $VISUAL or $EDITOR, which may include arguments such as
+// "code --wait".
+func openFromEnv(path string) error {
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		fields := strings.Fields(os.Getenv(key))
+		if len(fields) == 0 {
+			continue
+		}
+		bin, err := exec.LookPath(fields[0])
+		if err != nil {
+			return fmt.Errorf("%s=%q: %w", key, os.Getenv(key), err)
+		}
+		cmd := exec.Command(bin, append(fields[1:], path)...)
+		if e, ok := editors[filepath.Base(fields[0])]; ok && !e.terminal {
+			return start(cmd)
+		}
+		return runAttached(cmd)
+	}
+	return ErrNoEditor
+}
+
+// runAttached runs a terminal editor in the foreground.
+func runAttached(cmd *exec.Cmd) error {
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("open %s: %w", cmd.Args[len(cmd.Args)-1], err)
+	}
+	return nil
+}
+
+// run waits for short-lived launchers such as open.
+func run(cmd *exec.Cmd) error {
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("open %s: %w: %s", cmd.Args[len(cmd.Args)-1], err, msg)
+		}
+		return fmt.Errorf("open %s: %w", cmd.Args[len(cmd.Args)-1], err)
+	}
+	return nil
+}
+
+// start launches a GUI editor without waiting for it to exit.
+func start(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("open %s: %w", cmd.Args[len(cmd.Args)-1], err)
+	}
+	return cmd.Process.Release()
+}
@@ -0,0 +1,141 @@
+package editor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubEditors installs recording stand-ins for the given binaries in an
+// otherwise empty PATH. Each writes its arguments, one per line, to
+// <name>.args in the returned directory.
+func stubEditors(t *testing.T, names ...string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on windows")
+	}
+	mv, err := exec.LookPath("mv")
+	if err != nil {
+		t.Skip("mv not found")
+	}
+	dir := t.TempDir()
+	for _, name := range names {
+		out := filepath.Join(dir, name+".args")
+		script := "#!/bin/sh\nfor a in \"$@\"; do printf '%s\\n' \"$a\" >> \"" + out + ".tmp\"; done\n" + mv + " \"" + out + ".tmp\" \"" + out + "\"\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	return dir
+}
+
+// waitForArgs waits for a stub that may have been started in the background.
+func waitForArgs(t *testing.T, dir, name string) []string {
+	t.Helper()
+	path := filepath.Join(dir, name+".args")
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil {
+			return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s never ran", name)
+	return nil
+}
+
+func TestOpenNamedEditor(t *testing.T) {
+	abs, err := filepath.Abs("-notes.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"code", "Cursor", "zed", "idea", "nvim"} {
+		t.Run(name, func(t *testing.T) {
+			bin := strings.ToLower(name)
+			dir := stubEditors(t, bin)
+			if err := Open("-notes.md", name); err != nil {
+				t.Fatalf("Open error: %v", err)
+			}
+			if got := waitForArgs(t, dir, bin); !slices.Equal(got, []string{abs}) {
+				t.Errorf("%s args = %q, want %q", bin, got, []string{abs})
+			}
+		})
+	}
+}
+
+func TestOpenFromEnv(t *testing.T) {
+	tests := []struct {
+		name         string
+		visual, edit string
+		bin          string
+		wantArgs     []string
+	}{
+		{"EDITOR with arguments", "", "myedit -n --flag", "myedit", []string{"-n", "--flag"}},
+		{"VISUAL wins over EDITOR", "code --wait", "myedit", "code", []string{"--wait"}},
+		{"plain EDITOR", "", "nvim", "nvim", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := stubEditors(t, "myedit", "code", "nvim")
+			t.Setenv("VISUAL", tt.visual)
+			t.Setenv("EDITOR", tt.edit)
+
+			target := filepath.Join(t.TempDir(), "file.go")
+			if err := Open(target, ""); err != nil {
+				t.Fatalf("Open error: %v", err)
+			}
+			want := append(tt.wantArgs, target)
+			if got := waitForArgs(t, dir, tt.bin); !slices.Equal(got, want) {
+				t.Errorf("%s args = %q, want %q", tt.bin, got, want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		visual, edit, want string
+	}{
+		{"", "", ""},
+		{"", "vim", ""},
+		{"", "/usr/local/bin/nvim", "nvim"},
+		{"zed --wait", "nvim", "zed"},
+		{"", "code -w", "code"},
+	}
+	for _, tt := range tests {
+		t.Setenv("VISUAL", tt.visual)
+		t.Setenv("EDITOR", tt.edit)
+		if got := Detect(); got != tt.want {
+			t.Errorf("Detect with VISUAL=%q EDITOR=%q = %q, want %q", tt.visual, tt.edit, got, tt.want)
+		}
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	stubEditors(t)
+
+	if err := Open("x", ""); !errors.Is(err, ErrNoEditor) {
+		t.Errorf("Open without editor = %v, want ErrNoEditor", err)
+	}
+	if err := Open("x", "notepad"); err == nil {
+		t.Error("Open with unknown editor succeeded, want error")
+	}
+	if runtime.GOOS != "darwin" {
+		if err := Open("x", "zed"); err == nil {
+			t.Error("Open with missing zed binary succeeded, want error")
+		}
+	}
+	t.Setenv("EDITOR", "no-such-editor")
+	if err := Open("x", ""); err == nil {
+		t.Error("Open with missing $EDITOR binary succeeded, want error")
+	}
+}
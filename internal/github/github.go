@@ -0,0 +1,134 @@
+// Package github is a small GitHub API client covering the REST and GraphQL
+// calls the CLI needs, so commands don't have to shell out to gh.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	host           = "github.com"
+	defaultBaseURL = "https://api.github.com"
+)
+
+// Client calls the GitHub API with an optional token.
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+	token   string
+}
+
+// NewClient returns a client authenticated with token. An empty token makes
+// unauthenticated requests.
+func NewClient(token string) *Client {
+	return &Client{
+		BaseURL: defaultBaseURL,
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+		token:   token,
+	}
+}
+
+// DefaultClient returns a client using the token found by ResolveToken,
+// which may read gh's hosts.yml and, on macOS, run `security` to query the
+// keychain.
+func DefaultClient() (*Client, error) {
+	tok, err := ResolveToken()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(tok), nil
+}
+
+// APIError is returned for non-2xx responses.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error returns the status code and GitHub's message.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("github: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("github: %d %s", e.StatusCode, e.Message)
+}
+
+// Do sends a REST request to path (relative to BaseURL). body, if non-nil,
+// is encoded as JSON, and the response is decoded into out if non-nil.
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		r = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+"/"+strings.TrimPrefix(path, "/"), r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var payload struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		_ = json.Unmarshal(data, &payload)
+		return &APIError{StatusCode: resp.StatusCode, Message: payload.Message}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// GraphQL runs query with variables and decodes the data field into out.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	body := map[string]any{"query": query, "variables": variables}
+	if err := c.Do(ctx, http.MethodPost, "graphql", body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			msgs[i] = e.Message
+		}
+		return fmt.Errorf("github graphql: %s", strings.Join(msgs, "; "))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, out)
+}
@@ -0,0 +1,175 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestClient(t *testing.T, token string, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c := NewClient(token)
+	c.BaseURL = srv.URL
+	return c
+}
+
+func TestDoSendsAuthAndDecodes(t *testing.T) {
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer secret")
+		}
+		if got := r.Header.Get("Accept"); got != "application/vnd.github+json" {
+			t.Errorf("Accept = %q", got)
+		}
+		if r.URL.Path != "/repos/o/r" {
+			t.Errorf("path = %q, want /repos/o/r", r.URL.Path)
+		}
+		w.Write([]byte(`{"full_name":"o/r","default_branch":"main"}`))
+	})
+
+	repo, err := c.Repository(context.Background(), "o", "r")
+	if err != nil {
+		t.Fatalf("Repository error: %v", err)
+	}
+	if repo.FullName != "o/r" || repo.DefaultBranch != "main" {
+		t.Errorf("Repository = %+v", repo)
+	}
+}
+
+func TestDoWithoutToken(t *testing.T) {
+	c := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization = %q, want none", got)
+		}
+		w.Write([]byte(`{}`))
+	})
+	if err := c.Do(context.Background(), http.MethodGet, "/user", nil, nil); err != nil {
+		t.Fatalf("Do error: %v", err)
+	}
+}
+
+func TestDoSendsJSONBody(t *testing.T) {
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if body["title"] != "hi" {
+			t.Errorf("body = %v", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"number":5}`))
+	})
+
+	var out Issue
+	if err := c.Do(context.Background(), http.MethodPost, "repos/o/r/issues", map[string]string{"title": "hi"}, &out); err != nil {
+		t.Fatalf("Do error: %v", err)
+	}
+	if out.Number != 5 {
+		t.Errorf("Number = %d, want 5", out.Number)
+	}
+}
+
+func TestDoAPIError(t *testing.T) {
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	})
+
+	_, err := c.PullRequest(context.Background(), "o", "r", 1)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "Not Found" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
+
+func TestDoAPIErrorWithoutJSON(t *testing.T) {
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>bad gateway</html>"))
+	})
+
+	err := c.Do(context.Background(), http.MethodGet, "user", nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusBadGateway)
+	}
+	if !strings.Contains(err.Error(), "Bad Gateway") {
+		t.Errorf("Error() = %q, want status text", err.Error())
+	}
+}
+
+func TestDoNoContent(t *testing.T) {
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	out := User{Login: "unchanged"}
+	if err := c.Do(context.Background(), http.MethodPut, "user/starred/o/r", nil, &out); err != nil {
+		t.Fatalf("Do error: %v", err)
+	}
+	if out.Login != "unchanged" {
+		t.Errorf("out = %+v, want untouched", out)
+	}
+}
+
+func TestGraphQL(t *testing.T) {
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("request = %s %s, want POST /graphql", r.Method, r.URL.Path)
+		}
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if req.Variables["owner"] != "o" {
+			t.Errorf("variables = %v", req.Variables)
+		}
+		w.Write([]byte(`{"data":{"viewer":{"login":"me"}}}`))
+	})
+
+	var out struct {
+		Viewer User `json:"viewer"`
+	}
+	if err := c.GraphQL(context.Background(), "query { viewer { login } }", map[string]any{"owner": "o"}, &out); err != nil {
+		t.Fatalf("GraphQL error: %v", err)
+	}
+	if out.Viewer.Login != "me" {
+		t.Errorf("Viewer.Login = %q, want %q", out.Viewer.Login, "me")
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	c := newTestClient(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":null,"errors":[{"message":"first"},{"message":"second"}]}`))
+	})
+
+	err := c.GraphQL(context.Background(), "query { x }", nil, nil)
+	if err == nil {
+		t.Fatal("GraphQL succeeded, want error")
+	}
+	if !strings.Contains(err.Error(), "first; second") {
+		t.Errorf("error = %q, want both messages", err.Error())
+	}
+}
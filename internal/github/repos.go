@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// User is a GitHub account.
+type User struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+// Repository is a GitHub repository.
+type Repository struct {
+	FullName      string `json:"full_name"`
+	Name          string `json:"name"`
+	Owner         User   `json:"owner"`
+	DefaultBranch string `json:"default_branch"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	HTMLURL       string `json:"html_url"`
+	Fork          bool   `json:"fork"`
+}
+
+// Ref is the head or base branch of a pull request.
+type Ref struct {
+	Ref  string     `json:"ref"`
+	SHA  string     `json:"sha"`
+	Repo Repository `json:"repo"`
+}
+
+// PullRequest is a GitHub pull request.
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	User    User   `json:"user"`
+	Head    Ref    `json:"head"`
+	Base    Ref    `json:"base"`
+}
+
+// Issue is a GitHub issue.
+type Issue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	User    User   `json:"user"`
+}
+
+// CurrentUser returns the authenticated user.
+func (c *Client) CurrentUser(ctx context.Context) (User, error) {
+	var u User
+	err := c.Do(ctx, http.MethodGet, "user", nil, &u)
+	return u, err
+}
+
+// Repository returns owner/repo.
+func (c *Client) Repository(ctx context.Context, owner, repo string) (Repository, error) {
+	var r Repository
+	err := c.Do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s", owner, repo), nil, &r)
+	return r, err
+}
+
+// Fork forks owner/repo into the authenticated user's account. GitHub
+// creates forks asynchronously, so the returned clone URLs may take a few
+// seconds to become usable.
+func (c *Client) Fork(ctx context.Context, owner, repo string) (Repository, error) {
+	var r Repository
+	err := c.Do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/forks", owner, repo), struct{}{}, &r)
+	return r, err
+}
+
+// PullRequest returns pull request number in owner/repo.
+func (c *Client) PullRequest(ctx context.Context, owner, repo string, number int) (PullRequest, error) {
+	var pr PullRequest
+	err := c.Do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repo, number), nil, &pr)
+	return pr, err
+}
+
+// Issue returns issue number in owner/repo.
+func (c *Client) Issue(ctx context.Context, owner, repo string, number int) (Issue, error) {
+	var is Issue
+	err := c.Do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s/issues/%d", owner, repo, number), nil, &is)
+	return is, err
+}
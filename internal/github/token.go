@@ -0,0 +1,153 @@
+package github
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ErrNoToken is returned by ResolveToken when no source has a token.
+var ErrNoToken = errors.New("no GitHub token found (set GH_TOKEN or run `gh auth login`)")
+
+// ResolveToken looks for a token in GH_TOKEN and GITHUB_TOKEN, in that order
+// to match gh, then in the gh CLI hosts file, then in the macOS keychain
+// entry gh writes.
+func ResolveToken() (string, error) {
+	for _, key := range []string{"GH_TOKEN", "GITHUB_TOKEN"} {
+		if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+			return v, nil
+		}
+	}
+	entry := ghHostsEntry(host)
+	if entry.token != "" {
+		return entry.token, nil
+	}
+	if tok := keychainToken(host, entry.user); tok != "" {
+		return tok, nil
+	}
+	return "", ErrNoToken
+}
+
+func ghConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh")
+}
+
+// hostsEntry is the part of a hosts.yml host block that token lookup needs.
+type hostsEntry struct {
+	token string
+	user  string
+}
+
+// ghHostsEntry reads the host block for host from gh's hosts.yml. The file
+// is simple enough that a line scanner avoids pulling in a YAML dependency.
+func ghHostsEntry(host string) hostsEntry {
+	dir := ghConfigDir()
+	if dir == "" {
+		return hostsEntry{}
+	}
+	f, err := os.Open(filepath.Join(dir, "hosts.yml"))
+	if err != nil {
+		return hostsEntry{}
+	}
+	defer f.Close()
+	return readHosts(f, host)
+}
+
+// readHosts returns the oauth_token and user set directly under host. Newer
+// gh versions also keep per-account entries under users.<login>; those are
+// nested deeper and skipped, so only the active account is used.
+func readHosts(r io.Reader, host string) hostsEntry {
+	var entry hostsEntry
+	inHost := false
+	childIndent := -1
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == 0 {
+			inHost = strings.TrimSuffix(trimmed, ":") == host
+			childIndent = -1
+			continue
+		}
+		if !inHost {
+			continue
+		}
+		if childIndent < 0 {
+			childIndent = indent
+		}
+		if indent != childIndent {
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch key {
+		case "oauth_token":
+			entry.token = value
+		case "user":
+			entry.user = value
+		}
+	}
+	return entry
+}
+
+// keychainLookup returns the password stored for service and account. It
+// is a variable so tests can stand in for the security command.
+var keychainLookup = func(service, account string) (string, error) {
+	if runtime.GOOS != "darwin" {
+		return "", errors.New("keychain is only available on macOS")
+	}
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	return string(out), err
+}
+
+// keychainToken reads the token gh stores in the macOS keychain. gh keeps
+// one entry per account under the same service, plus one with an empty
+// account for the active token, so the account is always passed: the
+// active user from hosts.yml if known, then the active-token entry.
+func keychainToken(host, user string) string {
+	accounts := []string{""}
+	if user != "" {
+		accounts = []string{user, ""}
+	}
+	for _, account := range accounts {
+		out, err := keychainLookup("gh:"+host, account)
+		if err != nil {
+			continue
+		}
+		tok := strings.TrimSpace(out)
+		if encoded, ok := strings.CutPrefix(tok, "go-keyring-base64:"); ok {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				continue
+			}
+			tok = string(decoded)
+		}
+		if tok != "" {
+			return tok
+		}
+	}
+	return ""
+}
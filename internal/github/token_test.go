@@ -0,0 +1,197 @@
+package github
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const multiAccountHosts = `github.com:
+    users:
+        work:
+            oauth_token: gho_work
+        me:
+            oauth_token: gho_me
+    git_protocol: ssh
+    user: me
+    oauth_token: gho_active
+enterprise.example.com:
+    oauth_token: gho_enterprise
+    user: me
+`
+
+func TestReadHosts(t *testing.T) {
+	tests := []struct {
+		name, hosts, host string
+		want              hostsEntry
+	}{
+		{"active account over users", multiAccountHosts, "github.com", hostsEntry{token: "gho_active", user: "me"}},
+		{"other host", multiAccountHosts, "enterprise.example.com", hostsEntry{token: "gho_enterprise", user: "me"}},
+		{"missing host", multiAccountHosts, "gitlab.com", hostsEntry{}},
+		{
+			"legacy single account",
+			"github.com:\n    user: me\n    oauth_token: \"gho_legacy\"\n    git_protocol: https\n",
+			"github.com",
+			hostsEntry{token: "gho_legacy", user: "me"},
+		},
+		{
+			"token kept in keychain",
+			"github.com:\n    users:\n        work:\n        me:\n    git_protocol: ssh\n    user: me\n",
+			"github.com",
+			hostsEntry{user: "me"},
+		},
+		{
+			"only per-account tokens",
+			"github.com:\n    users:\n        me:\n            oauth_token: gho_me\n    user: me\n",
+			"github.com",
+			hostsEntry{user: "me"},
+		},
+		{
+			"token of a later host",
+			"example.com:\n    user: other\ngithub.com:\n\toauth_token: gho_tab\n",
+			"github.com",
+			hostsEntry{token: "gho_tab"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readHosts(strings.NewReader(tt.hosts), tt.host); got != tt.want {
+				t.Errorf("readHosts(%q) = %+v, want %+v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeKeychain replaces keychainLookup with entries keyed by service and
+// account, recording every lookup.
+func fakeKeychain(t *testing.T, entries map[[2]string]string) *[][2]string {
+	t.Helper()
+	var calls [][2]string
+	orig := keychainLookup
+	keychainLookup = func(service, account string) (string, error) {
+		calls = append(calls, [2]string{service, account})
+		if v, ok := entries[[2]string{service, account}]; ok {
+			return v + "\n", nil
+		}
+		return "", errors.New("not found")
+	}
+	t.Cleanup(func() { keychainLookup = orig })
+	return &calls
+}
+
+func TestKeychainToken(t *testing.T) {
+	encoded := "go-keyring-base64:" + base64.StdEncoding.EncodeToString([]byte("gho_me"))
+	tests := []struct {
+		name      string
+		user      string
+		entries   map[[2]string]string
+		want      string
+		wantCalls [][2]string
+	}{
+		{
+			name: "active user's entry",
+			user: "me",
+			entries: map[[2]string]string{
+				{"gh:github.com", "work"}: "gho_work",
+				{"gh:github.com", "me"}:   encoded,
+				{"gh:github.com", ""}:     "gho_other",
+			},
+			want:      "gho_me",
+			wantCalls: [][2]string{{"gh:github.com", "me"}},
+		},
+		{
+			name: "falls back to active-token entry",
+			user: "me",
+			entries: map[[2]string]string{
+				{"gh:github.com", "work"}: "gho_work",
+				{"gh:github.com", ""}:     "gho_active",
+			},
+			want:      "gho_active",
+			wantCalls: [][2]string{{"gh:github.com", "me"}, {"gh:github.com", ""}},
+		},
+		{
+			name: "unknown user uses active-token entry only",
+			entries: map[[2]string]string{
+				{"gh:github.com", "work"}: "gho_work",
+				{"gh:github.com", ""}:     "gho_active",
+			},
+			want:      "gho_active",
+			wantCalls: [][2]string{{"gh:github.com", ""}},
+		},
+		{
+			name: "no entry for the active account",
+			user: "me",
+			entries: map[[2]string]string{
+				{"gh:github.com", "work"}: "gho_work",
+			},
+			want:      "",
+			wantCalls: [][2]string{{"gh:github.com", "me"}, {"gh:github.com", ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := fakeKeychain(t, tt.entries)
+			if got := keychainToken("github.com", tt.user); got != tt.want {
+				t.Errorf("keychainToken = %q, want %q", got, tt.want)
+			}
+			if !slices.Equal(*calls, tt.wantCalls) {
+				t.Errorf("lookups = %q, want %q", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestResolveTokenFromKeychain(t *testing.T) {
+	dir := t.TempDir()
+	hosts := "github.com:\n    users:\n        work:\n        me:\n    user: me\n"
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_CONFIG_DIR", dir)
+	fakeKeychain(t, map[[2]string]string{
+		{"gh:github.com", "work"}: "gho_work",
+		{"gh:github.com", "me"}:   "gho_me",
+	})
+
+	got, err := ResolveToken()
+	if err != nil {
+		t.Fatalf("ResolveToken error: %v", err)
+	}
+	if got != "gho_me" {
+		t.Errorf("ResolveToken = %q, want %q", got, "gho_me")
+	}
+}
+
+func TestResolveTokenFromHostsFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(multiAccountHosts), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_CONFIG_DIR", dir)
+
+	got, err := ResolveToken()
+	if err != nil {
+		t.Fatalf("ResolveToken error: %v", err)
+	}
+	if got != "gho_active" {
+		t.Errorf("ResolveToken = %q, want %q", got, "gho_active")
+	}
+
+	t.Setenv("GITHUB_TOKEN", "from_github_token")
+	if got, _ := ResolveToken(); got != "from_github_token" {
+		t.Errorf("ResolveToken with GITHUB_TOKEN = %q, want %q", got, "from_github_token")
+	}
+
+	t.Setenv("GH_TOKEN", "from_gh_token")
+	if got, _ := ResolveToken(); got != "from_gh_token" {
+		t.Errorf("ResolveToken with GH_TOKEN and GITHUB_TOKEN = %q, want GH_TOKEN's %q", got, "from_gh_token")
+	}
+}
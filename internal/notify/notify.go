@@ -0,0 +1,64 @@
+// Package notify shows desktop notifications, so long-running commands can
+// report when they finish while the terminal is in the background.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnsupported is returned by Send on platforms without a notifier.
+var ErrUnsupported = errors.New("notifications are not supported on " + runtime.GOOS)
+
+// Send shows a notification with title and message. On macOS it prefers
+// terminal-notifier and falls back to osascript; on Linux it uses
+// notify-send.
+func Send(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		if path, err := exec.LookPath("terminal-notifier"); err == nil {
+			// terminal-notifier has no -- separator, and a -message value
+			// starting with - is read as another option. Piped input is
+			// used as the message verbatim.
+			cmd = exec.Command(path, "-title", title)
+			cmd.Stdin = strings.NewReader(message)
+		} else {
+			script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+			cmd = exec.Command("osascript", "-e", script)
+		}
+	case "linux":
+		path, err := exec.LookPath("notify-send")
+		if err != nil {
+			return fmt.Errorf("notify-send not found in PATH: %w", err)
+		}
+		cmd = exec.Command(path, "--", title, message)
+	default:
+		return ErrUnsupported
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("send notification: %w: %s", err, msg)
+		}
+		return fmt.Errorf("send notification: %w", err)
+	}
+	return nil
+}
+
+// Result notifies about a finished command, using err to pick the wording.
+func Result(command string, err error) error {
+	if err != nil {
+		return Send(command+" failed", err.Error())
+	}
+	return Send(command+" finished", "Completed successfully")
+}
+
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,235 @@
+// Package redact masks secrets in diffs and other text before it is sent
+// to an AI provider.
+package redact
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Rule is a named secret pattern. If the pattern has a group named
+// "secret", only that group is masked; otherwise the whole match is.
+type Rule struct {
+	Name    string
+	Pattern string
+}
+
+// DefaultRules match common credential formats and secret assignments.
+var DefaultRules = []Rule{
+	{Name: "aws-access-key", Pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{Name: "github-token", Pattern: `\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`},
+	{Name: "api-key", Pattern: `\bsk-[A-Za-z0-9_-]{20,}`},
+	{Name: "slack-token", Pattern: `\bxox[abprs]-[A-Za-z0-9-]{10,}`},
+	{Name: "jwt", Pattern: `\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`},
+	{Name: "assignment", Pattern: `(?i)[\w.-]*(?:password|passwd|secret|token|api[_-]?key)[\w.-]*["']?\s*[:=]\s*["']?(?P<secret>[^\s"'(),;]{8,})(?:["'\s,;]|$)`},
+}
+
+// DefaultFiles are globs for files whose whole contents are withheld.
+var DefaultFiles = []string{".env*", "*.pem", "*.key", "*.p12", "*.pfx", "id_rsa*", "id_ed25519*"}
+
+var (
+	privateKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	privateKeyEnd   = regexp.MustCompile(`-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// Redaction records one masked value, without the value itself.
+type Redaction struct {
+	File string
+	Line int
+	Rule string
+}
+
+// String formats r as file:line: rule, or line N: rule for plain text.
+func (r Redaction) String() string {
+	if r.File == "" {
+		return fmt.Sprintf("line %d: %s", r.Line, r.Rule)
+	}
+	return fmt.Sprintf("%s:%d: %s", r.File, r.Line, r.Rule)
+}
+
+// Report lists redactions one per line, for --show-redactions style output.
+func Report(rs []Redaction) string {
+	var b strings.Builder
+	for _, r := range rs {
+		b.WriteString(r.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+type rule struct {
+	name   string
+	re     *regexp.Regexp
+	secret int
+}
+
+// Redactor masks secrets matching its rules and withholds files matching
+// its globs.
+type Redactor struct {
+	rules []rule
+	files []string
+}
+
+// New compiles rules and validates file globs.
+func New(rules []Rule, files []string) (*Redactor, error) {
+	r := &Redactor{files: files}
+	for _, rl := range rules {
+		re, err := regexp.Compile(rl.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redact rule %q: %w", rl.Name, err)
+		}
+		r.rules = append(r.rules, rule{name: rl.Name, re: re, secret: re.SubexpIndex("secret")})
+	}
+	for _, glob := range files {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("redact file glob %q: %w", glob, err)
+		}
+	}
+	return r, nil
+}
+
+// Default returns a Redactor using DefaultRules and DefaultFiles.
+func Default() *Redactor {
+	r, err := New(DefaultRules, DefaultFiles)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Placeholder is the text that replaces a value masked by rule.
+func Placeholder(rule string) string {
+	return "[REDACTED:" + rule + "]"
+}
+
+// Text masks secrets in s.
+func (r *Redactor) Text(s string) (string, []Redaction) {
+	return r.redact(s, false)
+}
+
+// Diff masks secrets in a unified git diff. Sections for files matching a
+// file glob are replaced by a single placeholder line after their
+// diff --git header.
+func (r *Redactor) Diff(diff string) (string, []Redaction) {
+	return r.redact(diff, true)
+}
+
+func (r *Redactor) redact(s string, isDiff bool) (string, []Redaction) {
+	var (
+		out      strings.Builder
+		found    []Redaction
+		file     string
+		skipping bool
+		inKey    bool
+	)
+	for i, line := range strings.SplitAfter(s, "\n") {
+		if line == "" {
+			continue
+		}
+		lineNo := i + 1
+
+		if isDiff && strings.HasPrefix(line, "diff --git ") {
+			file = diffPath(line)
+			inKey = false
+			out.WriteString(line)
+			glob, ok := r.matchFile(file)
+			skipping = ok
+			if ok {
+				found = append(found, Redaction{File: file, Line: lineNo, Rule: "file " + glob})
+				out.WriteString(Placeholder("file") + " contents of " + file + " withheld\n")
+			}
+			continue
+		}
+		if skipping {
+			continue
+		}
+
+		if inKey || privateKeyBegin.MatchString(line) {
+			if !inKey {
+				found = append(found, Redaction{File: file, Line: lineNo, Rule: "private-key"})
+			}
+			inKey = !privateKeyEnd.MatchString(line)
+			out.WriteString(maskWholeLine(line, isDiff))
+			continue
+		}
+
+		masked, names := r.maskLine(line)
+		for _, name := range names {
+			found = append(found, Redaction{File: file, Line: lineNo, Rule: name})
+		}
+		out.WriteString(masked)
+	}
+	return out.String(), found
+}
+
+// maskLine applies every rule to line and returns the rule name for each
+// masked value.
+func (r *Redactor) maskLine(line string) (string, []string) {
+	var names []string
+	for _, rl := range r.rules {
+		matches := rl.re.FindAllStringSubmatchIndex(line, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			start, end := m[0], m[1]
+			if rl.secret >= 0 && m[2*rl.secret] >= 0 {
+				start, end = m[2*rl.secret], m[2*rl.secret+1]
+			}
+			// An earlier rule already masked this value.
+			if strings.HasPrefix(line[start:end], "[REDACTED:") || start < last {
+				continue
+			}
+			b.WriteString(line[last:start])
+			b.WriteString(Placeholder(rl.name))
+			last = end
+			names = append(names, rl.name)
+		}
+		b.WriteString(line[last:])
+		line = b.String()
+	}
+	return line, names
+}
+
+// maskWholeLine replaces a line's content, keeping a diff line's +, - or
+// space prefix and the line ending.
+func maskWholeLine(line string, isDiff bool) string {
+	prefix := ""
+	if isDiff && line != "" && strings.ContainsRune("+- ", rune(line[0])) {
+		prefix = line[:1]
+	}
+	ending := ""
+	if strings.HasSuffix(line, "\n") {
+		ending = "\n"
+	}
+	return prefix + Placeholder("private-key") + ending
+}
+
+// matchFile returns the first glob matching file's full path or base name.
+func (r *Redactor) matchFile(file string) (string, bool) {
+	if file == "" {
+		return "", false
+	}
+	for _, glob := range r.files {
+		if ok, _ := path.Match(glob, file); ok {
+			return glob, true
+		}
+		if ok, _ := path.Match(glob, path.Base(file)); ok {
+			return glob, true
+		}
+	}
+	return "", false
+}
+
+// diffPath returns the b/ path from a "diff --git a/x b/y" header.
+func diffPath(header string) string {
+	header = strings.TrimRight(header, "\r\n")
+	if i := strings.LastIndex(header, " b/"); i >= 0 {
+		return header[i+3:]
+	}
+	return ""
+}
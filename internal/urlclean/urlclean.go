@@ -0,0 +1,249 @@
+// Package urlclean removes tracking parameters from URLs, rewrites mobile
+// and AMP variants to their canonical form, and resolves link shorteners.
+package urlclean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"igshid":  true,
+	"ref_src": true,
+	"ref_url": true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+	"yclid":   true,
+	"twclid":  true,
+}
+
+// hostParams are tracking parameters that only mean tracking on some hosts.
+var hostParams = map[string][]string{
+	"youtube.com":      {"si", "feature", "pp"},
+	"open.spotify.com": {"si"},
+	"twitter.com":      {"s", "t"},
+	"x.com":            {"s", "t"},
+}
+
+var mobileHosts = map[string]string{
+	"m.youtube.com":      "youtube.com",
+	"mobile.twitter.com": "twitter.com",
+	"mobile.x.com":       "x.com",
+	"m.facebook.com":     "facebook.com",
+	"m.reddit.com":       "reddit.com",
+}
+
+var shorteners = map[string]bool{
+	"bit.ly":      true,
+	"buff.ly":     true,
+	"goo.gl":      true,
+	"is.gd":       true,
+	"lnkd.in":     true,
+	"ow.ly":       true,
+	"t.co":        true,
+	"t.ly":        true,
+	"tinyurl.com": true,
+}
+
+// Clean strips tracking parameters and normalizes mobile and AMP URLs
+// without touching the network.
+func Clean(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("parse %q: %w", raw, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("not an absolute URL: %q", raw)
+	}
+
+	u = unwrapAMP(u)
+
+	host := strings.ToLower(u.Hostname())
+	if canonical, ok := mobileHosts[host]; ok {
+		host = canonical
+	} else if lang, rest, ok := strings.Cut(host, ".m."); ok && strings.HasSuffix(rest, "wikipedia.org") {
+		host = lang + "." + rest
+	}
+
+	if host == "youtu.be" {
+		id := strings.Trim(u.Path, "/")
+		if id != "" {
+			v := "v=" + url.QueryEscape(id)
+			if u.RawQuery != "" {
+				v += "&" + u.RawQuery
+			}
+			u.Path = "/watch"
+			u.RawPath = ""
+			u.RawQuery = v
+			host = "youtube.com"
+		}
+	}
+	switch port := u.Port(); {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	u.RawQuery = stripParams(u.RawQuery, hostParams[strings.TrimPrefix(host, "www.")])
+	return u.String(), nil
+}
+
+// stripParams drops tracking parameters from a raw query string. Kept
+// parameters are left exactly as they were, including their order and
+// encoding, since some sites treat ?a and ?a= differently.
+func stripParams(rawQuery string, extra []string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	parts := strings.Split(rawQuery, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		key = strings.ToLower(key)
+		if strings.HasPrefix(key, "utm_") || trackingParams[key] || slices.Contains(extra, key) {
+			continue
+		}
+		kept = append(kept, part)
+	}
+	return strings.Join(kept, "&")
+}
+
+// unwrapAMP turns Google AMP cache URLs and /amp paths back into the
+// original article URL.
+func unwrapAMP(u *url.URL) *url.URL {
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	prefix := ""
+	switch {
+	case strings.HasPrefix(host, "google."):
+		prefix = "/amp/s/"
+	case strings.HasSuffix(host, ".cdn.ampproject.org"):
+		prefix = "/c/s/"
+	}
+	rest, ok := strings.CutPrefix(u.Path, prefix)
+	if !ok || prefix == "" {
+		return u
+	}
+	orig, err := url.Parse("https://" + rest)
+	if err != nil {
+		return u
+	}
+	orig.RawQuery = u.RawQuery
+
+	// Publishers serving AMP through the cache usually put the AMP page at
+	// <article>/amp. Only trust that convention for cached URLs; elsewhere
+	// a path ending in /amp is an ordinary page.
+	if p, ok := strings.CutSuffix(strings.TrimSuffix(orig.Path, "/"), "/amp"); ok {
+		orig.Path = p
+		if orig.Path == "" {
+			orig.Path = "/"
+		}
+		orig.RawPath = ""
+	}
+	return orig
+}
+
+// IsShortened reports whether raw points at a known link shortener.
+func IsShortened(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+	return shorteners[strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")]
+}
+
+const maxRedirects = 10
+
+// Unshorten follows redirects from a known shortener and returns the final
+// destination. URLs that aren't shortened are returned unchanged.
+func Unshorten(ctx context.Context, raw string) (string, error) {
+	if !IsShortened(raw) {
+		return raw, nil
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	current := strings.TrimSpace(raw)
+	for range maxRedirects {
+		resp, err := fetch(ctx, client, http.MethodHead, current)
+		if err != nil {
+			return "", err
+		}
+		// Some shorteners reject HEAD; ask again with GET before giving up.
+		if resp.StatusCode >= 400 {
+			if resp, err = fetch(ctx, client, http.MethodGet, current); err != nil {
+				return "", err
+			}
+		}
+		if resp.StatusCode >= 400 {
+			return "", fmt.Errorf("resolve %s: %s", current, resp.Status)
+		}
+
+		loc := resp.Header.Get("Location")
+		if resp.StatusCode < 300 || resp.StatusCode > 399 || loc == "" {
+			return current, nil
+		}
+		base, err := url.Parse(current)
+		if err != nil {
+			return "", err
+		}
+		next, err := base.Parse(loc)
+		if err != nil {
+			return "", fmt.Errorf("invalid redirect %q: %w", loc, err)
+		}
+		current = next.String()
+		if !IsShortened(current) {
+			return current, nil
+		}
+	}
+	return "", errors.New("too many redirects resolving " + raw)
+}
+
+// fetch sends one request without following redirects and discards the
+// body, so only the status and headers are kept.
+func fetch(ctx context.Context, client *http.Client, method, target string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", target, err)
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+	return resp, nil
+}
+
+// Normalize unshortens raw and then cleans the result.
+func Normalize(ctx context.Context, raw string) (string, error) {
+	resolved, err := Unshorten(ctx, raw)
+	if err != nil {
+		return "", err
+	}
+	return Clean(resolved)
+}
@@ -0,0 +1,238 @@
+package urlclean
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestCleanAMP(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://www.google.com/amp/s/example.com/news/story/amp", "https://example.com/news/story"},
+		{"https://www.google.com/amp/s/example.com/news/story", "https://example.com/news/story"},
+		{"https://example-com.cdn.ampproject.org/c/s/example.com/news/story/amp/", "https://example.com/news/story"},
+		{"https://www.google.com/amp/s/example.com/amp", "https://example.com/"},
+
+		// Paths ending in /amp are ordinary pages outside the AMP caches.
+		{"https://github.com/someone/amp", "https://github.com/someone/amp"},
+		{"https://example.com/news/story/amp", "https://example.com/news/story/amp"},
+		{"https://example.com/amp/", "https://example.com/amp/"},
+		{"https://www.google.com/search?q=amp", "https://www.google.com/search?q=amp"},
+	}
+	for _, tt := range tests {
+		got, err := Clean(tt.in)
+		if err != nil {
+			t.Errorf("Clean(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Clean(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCleanQuery(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://example.com/a?utm_source=x&id=3&fbclid=1", "https://example.com/a?id=3"},
+		{"https://example.com/a?UTM_Medium=x&id=3", "https://example.com/a?id=3"},
+		{"https://example.com/a?utm%5Fsource=x&id=3", "https://example.com/a?id=3"},
+		{"https://example.com/a?utm_source=x&gclid=y", "https://example.com/a"},
+		{"https://example.com/a?b=1&a=2", "https://example.com/a?b=1&a=2"},
+		{"https://example.com/a?b=1&utm_campaign=z&a=2", "https://example.com/a?b=1&a=2"},
+		{"https://example.com/a?foo", "https://example.com/a?foo"},
+		{"https://example.com/a?foo&fbclid=1&bar=", "https://example.com/a?foo&bar="},
+		{"https://example.com/a?q=a%20b+c", "https://example.com/a?q=a%20b+c"},
+		{"https://example.com/a?si=1", "https://example.com/a?si=1"},
+		{"https://example.com/?amp=1&x=2", "https://example.com/?amp=1&x=2"},
+		{"https://example.com/a?amp", "https://example.com/a?amp"},
+		{"https://example.com/a?id=3#utm_source=x", "https://example.com/a?id=3#utm_source=x"},
+
+		{"https://www.youtube.com/watch?v=abc&si=zz", "https://www.youtube.com/watch?v=abc"},
+		{"https://www.youtube.com/watch?v=abc&t=10", "https://www.youtube.com/watch?v=abc&t=10"},
+		{"https://m.youtube.com/watch?v=abc&si=zz", "https://youtube.com/watch?v=abc"},
+		{"https://youtu.be/abc?si=q&t=10", "https://youtube.com/watch?v=abc&t=10"},
+		{"https://open.spotify.com/track/1?si=abc", "https://open.spotify.com/track/1"},
+		{"https://x.com/a/status/1?s=20&t=abc", "https://x.com/a/status/1"},
+		{"https://mobile.twitter.com/a/status/1", "https://twitter.com/a/status/1"},
+		{"https://en.m.wikipedia.org/wiki/Go", "https://en.wikipedia.org/wiki/Go"},
+	}
+	for _, tt := range tests {
+		got, err := Clean(tt.in)
+		if err != nil {
+			t.Errorf("Clean(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Clean(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCleanHost(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"http://[::1]:8080/x?utm_source=y", "http://[::1]:8080/x"},
+		{"http://[::1]/x", "http://[::1]/x"},
+		{"http://[2001:db8::1]:443/x?a=1", "http://[2001:db8::1]:443/x?a=1"},
+		{"http://127.0.0.1:8080/x", "http://127.0.0.1:8080/x"},
+		{"https://Example.COM:8443/Path", "https://example.com:8443/Path"},
+	}
+	for _, tt := range tests {
+		got, err := Clean(tt.in)
+		if err != nil {
+			t.Errorf("Clean(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Clean(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCleanErrors(t *testing.T) {
+	for _, in := range []string{"", "example.com/a", "/relative/path", "http://%zz"} {
+		if got, err := Clean(in); err == nil {
+			t.Errorf("Clean(%q) = %q, want error", in, got)
+		}
+	}
+}
+
+func TestIsShortened(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"https://bit.ly/abc", true},
+		{"https://www.bit.ly/abc", true},
+		{"https://T.CO/abc", true},
+		{"http://tinyurl.com/abc", true},
+		{"https://example.com/abc", false},
+		{"https://notbit.ly/abc", false},
+		{"https://bit.ly.example.com/abc", false},
+		{"not a url", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsShortened(tt.in); got != tt.want {
+			t.Errorf("IsShortened(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// shortenerServer serves redirects and registers its host as a shortener
+// for the duration of the test.
+func shortenerServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shorteners[u.Hostname()] = true
+	t.Cleanup(func() { delete(shorteners, u.Hostname()) })
+	return srv
+}
+
+func TestUnshorten(t *testing.T) {
+	srv := shortenerServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			http.Redirect(w, r, "https://example.com/final?utm_source=x", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+	ctx := context.Background()
+
+	got, err := Unshorten(ctx, srv.URL+"/a")
+	if err != nil {
+		t.Fatalf("Unshorten error: %v", err)
+	}
+	if want := "https://example.com/final?utm_source=x"; got != want {
+		t.Errorf("Unshorten = %q, want %q", got, want)
+	}
+
+	got, err = Normalize(ctx, srv.URL+"/a")
+	if err != nil {
+		t.Fatalf("Normalize error: %v", err)
+	}
+	if want := "https://example.com/final"; got != want {
+		t.Errorf("Normalize = %q, want %q", got, want)
+	}
+
+	got, err = Unshorten(ctx, srv.URL+"/landing")
+	if err != nil {
+		t.Fatalf("Unshorten error: %v", err)
+	}
+	if want := srv.URL + "/landing"; got != want {
+		t.Errorf("Unshorten without redirect = %q, want %q", got, want)
+	}
+
+	if _, err := Unshorten(ctx, srv.URL+"/loop"); err == nil {
+		t.Error("Unshorten on a redirect loop succeeded, want error")
+	}
+}
+
+func TestUnshortenPassthrough(t *testing.T) {
+	in := "https://example.com/a?b=1"
+	got, err := Unshorten(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Unshorten error: %v", err)
+	}
+	if got != in {
+		t.Errorf("Unshorten(%q) = %q, want unchanged", in, got)
+	}
+}
+
+func TestUnshortenFallsBackToGET(t *testing.T) {
+	var methods []string
+	srv := shortenerServer(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/gone":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case r.URL.Path == "/b":
+			w.Header().Set("Location", "https://example.com/final")
+			w.WriteHeader(http.StatusFound)
+			w.Write([]byte("<html>body is discarded</html>"))
+		}
+	})
+	ctx := context.Background()
+
+	got, err := Unshorten(ctx, srv.URL+"/a")
+	if err != nil {
+		t.Fatalf("Unshorten error: %v", err)
+	}
+	if want := "https://example.com/final"; got != want {
+		t.Errorf("Unshorten = %q, want %q", got, want)
+	}
+	wantMethods := []string{"HEAD /a", "GET /a", "HEAD /b", "GET /b"}
+	if !slices.Equal(methods, wantMethods) {
+		t.Errorf("requests = %q, want %q", methods, wantMethods)
+	}
+
+	if got, err := Normalize(ctx, srv.URL+"/gone"); err == nil {
+		t.Errorf("Normalize on a 404 shortener = %q, want error", got)
+	}
+}
@@ -0,0 +1,325 @@
+// Package urlparse parses the many forms of GitHub repository URLs into a
+// single typed value.
+package urlparse
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind is the GitHub page a URL points at.
+type Kind int
+
+const (
+	Repo     Kind = iota // repository root or any page without more detail
+	Tree                 // directory at a ref: /tree/<ref>/<path>
+	Blob                 // file at a ref: /blob/<ref>/<path>
+	Pull                 // pull request: /pull/<n>
+	Issue                // issue: /issues/<n>
+	Commit               // single commit: /commit/<sha>
+	Releases             // release list: /releases
+	Release              // single release: /releases/tag/<tag>
+)
+
+// String returns the lowercase name of k.
+func (k Kind) String() string {
+	switch k {
+	case Repo:
+		return "repo"
+	case Tree:
+		return "tree"
+	case Blob:
+		return "blob"
+	case Pull:
+		return "pull"
+	case Issue:
+		return "issue"
+	case Commit:
+		return "commit"
+	case Releases:
+		return "releases"
+	case Release:
+		return "release"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// URL is a parsed GitHub URL. Ref is set for tree, blob and commit URLs,
+// Path for tree and blob URLs, Number for pull and issue URLs, and Tag for
+// release URLs.
+type URL struct {
+	Host   string
+	Owner  string
+	Repo   string
+	Kind   Kind
+	Ref    string
+	Path   string
+	Number int
+	Tag    string
+}
+
+const githubHost = "github.com"
+
+// ErrNotGitHub is returned for URLs and paths that don't point at github.com.
+var ErrNotGitHub = errors.New("not a GitHub URL")
+
+// Parse accepts https and http URLs (with or without scheme or www prefix),
+// SSH URLs in both scp and ssh:// form, and bare owner/repo shorthand.
+// Trailing .git, query strings and fragments are ignored. Inputs that look
+// like local paths, such as /Users/me/repo or src/main.go, are rejected
+// rather than read as shorthand.
+//
+// Branch names containing slashes are ambiguous in tree and blob URLs;
+// Parse takes the first segment as the ref and the rest as the path.
+func Parse(raw string) (URL, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return URL{}, errors.New("empty URL")
+	}
+
+	if rest, ok := strings.CutPrefix(s, "git@"); ok {
+		host, path, found := strings.Cut(rest, ":")
+		if !found {
+			return URL{}, fmt.Errorf("invalid SSH URL %q", raw)
+		}
+		return parsePath(host, stripQuery(path), raw)
+	}
+
+	if !strings.Contains(s, "://") {
+		first, _, _ := strings.Cut(s, "/")
+		if !strings.Contains(first, ".") {
+			if strings.Contains(s, "/") && !isShorthand(s) {
+				return URL{}, fmt.Errorf("%w: %q is not owner/repo shorthand", ErrNotGitHub, raw)
+			}
+			return parsePath(githubHost, stripQuery(s), raw)
+		}
+		s = "https://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return URL{}, fmt.Errorf("parse %q: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "ssh", "git":
+	default:
+		return URL{}, fmt.Errorf("unsupported scheme %q in %q", u.Scheme, raw)
+	}
+	// u.Path is already decoded and free of the query and fragment.
+	return parsePath(u.Hostname(), u.Path, raw)
+}
+
+// stripQuery drops a query string or fragment from scp-style and shorthand
+// paths, which don't go through url.Parse.
+func stripQuery(s string) string {
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+var (
+	loginPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?$`)
+	repoPattern  = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// shorthandKinds are the page segments allowed after owner/repo shorthand.
+var shorthandKinds = map[string]bool{
+	"tree":     true,
+	"blob":     true,
+	"pull":     true,
+	"issues":   true,
+	"commit":   true,
+	"releases": true,
+}
+
+// fileExts are extensions that mark a shorthand candidate as a local file
+// path rather than a repository. Extensions that real repositories use,
+// such as .js in next.js, are deliberately left out.
+var fileExts = map[string]bool{
+	".go": true, ".rs": true, ".py": true, ".rb": true, ".c": true, ".h": true,
+	".cc": true, ".cpp": true, ".java": true, ".kt": true, ".swift": true,
+	".sh": true, ".md": true, ".txt": true, ".json": true, ".yml": true,
+	".yaml": true, ".toml": true, ".lock": true, ".mod": true, ".sum": true,
+	".html": true, ".css": true,
+}
+
+// isShorthand reports whether s is owner/repo[.git], optionally followed by
+// a page such as /tree/<ref>. Anything else, like /Users/me/repo, ~/gh/x or
+// src/main.go, is a local path.
+func isShorthand(s string) bool {
+	parts := strings.Split(strings.TrimSuffix(stripQuery(s), "/"), "/")
+	if len(parts) < 2 || !loginPattern.MatchString(parts[0]) {
+		return false
+	}
+	repo := parts[1]
+	if !repoPattern.MatchString(repo) || repo == "." || repo == ".." {
+		return false
+	}
+	if len(parts) > 2 {
+		return shorthandKinds[parts[2]]
+	}
+	return !fileExts[path.Ext(strings.TrimSuffix(repo, ".git"))]
+}
+
+// reservedOwners are top-level github.com routes that can't be user or
+// organization names, so URLs under them never point at a repository.
+var reservedOwners = map[string]bool{
+	"about":         true,
+	"account":       true,
+	"apps":          true,
+	"codespaces":    true,
+	"collections":   true,
+	"dashboard":     true,
+	"enterprise":    true,
+	"events":        true,
+	"explore":       true,
+	"features":      true,
+	"issues":        true,
+	"login":         true,
+	"logout":        true,
+	"marketplace":   true,
+	"new":           true,
+	"notifications": true,
+	"organizations": true,
+	"orgs":          true,
+	"pricing":       true,
+	"pulls":         true,
+	"search":        true,
+	"settings":      true,
+	"signup":        true,
+	"sponsors":      true,
+	"stars":         true,
+	"topics":        true,
+	"trending":      true,
+	"users":         true,
+}
+
+func parsePath(host, path, raw string) (URL, error) {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	if host != githubHost {
+		return URL{}, fmt.Errorf("%w: %q", ErrNotGitHub, raw)
+	}
+
+	var parts []string
+	for _, p := range strings.Split(path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) < 2 {
+		return URL{}, fmt.Errorf("missing owner or repository in %q", raw)
+	}
+	if reservedOwners[strings.ToLower(parts[0])] {
+		return URL{}, fmt.Errorf("%q is a GitHub %s page, not a repository", raw, strings.ToLower(parts[0]))
+	}
+
+	out := URL{
+		Host:  host,
+		Owner: parts[0],
+		Repo:  strings.TrimSuffix(parts[1], ".git"),
+		Kind:  Repo,
+	}
+	if out.Repo == "" {
+		return URL{}, fmt.Errorf("missing repository in %q", raw)
+	}
+
+	rest := parts[2:]
+	if len(rest) == 0 {
+		return out, nil
+	}
+
+	// Pages without a ref or number, such as /issues, /issues/new or /tree,
+	// still identify the repository, so they fall back to Repo.
+	switch rest[0] {
+	case "tree", "blob":
+		if len(rest) < 2 {
+			break
+		}
+		out.Kind = Tree
+		if rest[0] == "blob" {
+			out.Kind = Blob
+		}
+		out.Ref = rest[1]
+		out.Path = strings.Join(rest[2:], "/")
+	case "pull", "issues":
+		if len(rest) < 2 {
+			break
+		}
+		n, err := strconv.Atoi(rest[1])
+		if err != nil || n <= 0 {
+			break
+		}
+		out.Kind = Pull
+		if rest[0] == "issues" {
+			out.Kind = Issue
+		}
+		out.Number = n
+	case "commit":
+		if len(rest) < 2 {
+			break
+		}
+		out.Kind = Commit
+		out.Ref = rest[1]
+	case "releases":
+		out.Kind = Releases
+		if len(rest) >= 3 && rest[1] == "tag" {
+			out.Kind = Release
+			out.Tag = strings.Join(rest[2:], "/")
+		}
+	}
+	return out, nil
+}
+
+// FullName returns owner/repo.
+func (u URL) FullName() string {
+	return u.Owner + "/" + u.Repo
+}
+
+// CloneURL returns the HTTPS clone URL.
+func (u URL) CloneURL() string {
+	return "https://" + u.Host + "/" + u.FullName() + ".git"
+}
+
+// SSHURL returns the scp-style SSH clone URL.
+func (u URL) SSHURL() string {
+	return "git@" + u.Host + ":" + u.FullName() + ".git"
+}
+
+// WebURL returns the canonical https URL for the page u points at.
+func (u URL) WebURL() string {
+	base := "https://" + u.Host + "/" + u.FullName()
+	switch u.Kind {
+	case Tree, Blob:
+		s := base + "/" + u.Kind.String() + "/" + url.PathEscape(u.Ref)
+		if u.Path != "" {
+			s += "/" + escapePath(u.Path)
+		}
+		return s
+	case Pull:
+		return base + "/pull/" + strconv.Itoa(u.Number)
+	case Issue:
+		return base + "/issues/" + strconv.Itoa(u.Number)
+	case Commit:
+		return base + "/commit/" + url.PathEscape(u.Ref)
+	case Releases:
+		return base + "/releases"
+	case Release:
+		return base + "/releases/tag/" + escapePath(u.Tag)
+	}
+	return base
+}
+
+// escapePath escapes each segment of p, keeping the separating slashes.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,191 @@
+package urlparse
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	repo := URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Repo}
+	tests := []struct {
+		in   string
+		want URL
+	}{
+		{"https://github.com/o/r", repo},
+		{"http://github.com/o/r", repo},
+		{"https://www.github.com/o/r", repo},
+		{"https://GitHub.com/o/r", repo},
+		{"github.com/o/r", repo},
+		{"www.github.com/o/r", repo},
+		{"https://github.com/o/r/", repo},
+		{"https://github.com/o/r.git", repo},
+		{"github.com/o/r.git", repo},
+		{"https://github.com/o/r?tab=readme-ov-file", repo},
+		{"https://github.com/o/r#readme", repo},
+		{"https://github.com/o/r.git?x=1#y", repo},
+		{"  https://github.com/o/r\n", repo},
+		{"git@github.com:o/r.git", repo},
+		{"git@github.com:o/r", repo},
+		{"ssh://git@github.com/o/r.git", repo},
+		{"ssh://git@github.com:22/o/r.git", repo},
+		{"git://github.com/o/r.git", repo},
+		{"o/r", repo},
+		{"o/r.git", repo},
+		{"o/r/", repo},
+		{"o/r#readme", repo},
+		{"some-org/r", URL{Host: "github.com", Owner: "some-org", Repo: "r", Kind: Repo}},
+		{"vercel/next.js", URL{Host: "github.com", Owner: "vercel", Repo: "next.js", Kind: Repo}},
+		{"o/.github", URL{Host: "github.com", Owner: "o", Repo: ".github", Kind: Repo}},
+		{"o/r/tree/main/a", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Tree, Ref: "main", Path: "a"}},
+		{"o/r/pull/3", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Pull, Number: 3}},
+
+		{"https://github.com/o/r/tree/main", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Tree, Ref: "main"}},
+		{"https://github.com/o/r/tree/main/a/b", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Tree, Ref: "main", Path: "a/b"}},
+		{"https://github.com/o/r/blob/v1.0/main.go", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Blob, Ref: "v1.0", Path: "main.go"}},
+		{"https://github.com/o/r/blob/main/cmd/x.go#L10-L20", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Blob, Ref: "main", Path: "cmd/x.go"}},
+		{"https://github.com/o/r/pull/12", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Pull, Number: 12}},
+		{"https://github.com/o/r/pull/12/files", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Pull, Number: 12}},
+		{"https://github.com/o/r/pull/12#issuecomment-1", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Pull, Number: 12}},
+		{"https://github.com/o/r/issues/7", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Issue, Number: 7}},
+		{"https://github.com/o/r/commit/abc123", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Commit, Ref: "abc123"}},
+		{"https://github.com/o/r/releases", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Releases}},
+		{"https://github.com/o/r/releases/tag/v1.2.3", URL{Host: "github.com", Owner: "o", Repo: "r", Kind: Release, Tag: "v1.2.3"}},
+
+		// Pages that carry no ref or number still name the repository.
+		{"https://github.com/o/r/issues", repo},
+		{"https://github.com/o/r/issues/new", repo},
+		{"https://github.com/o/r/pull", repo},
+		{"https://github.com/o/r/pulls", repo},
+		{"https://github.com/o/r/pulls/12", repo},
+		{"https://github.com/o/r/pull/0", repo},
+		{"https://github.com/o/r/tree", repo},
+		{"https://github.com/o/r/blob", repo},
+		{"https://github.com/o/r/commit", repo},
+		{"https://github.com/o/r/commits/main", repo},
+		{"https://github.com/o/r/actions", repo},
+		{"https://github.com/o/r/compare/a...b", repo},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in        string
+		notGitHub bool
+	}{
+		{in: ""},
+		{in: "   "},
+		{in: "o"},
+		{in: "https://github.com"},
+		{in: "https://github.com/o"},
+		{in: "https://github.com/o/.git"},
+		{in: "git@github.com"},
+		{in: "ftp://github.com/o/r"},
+
+		// Reserved routes are GitHub pages, not owner/repo.
+		{in: "https://github.com/orgs/foo/repositories"},
+		{in: "https://github.com/settings/profile"},
+		{in: "https://github.com/marketplace/actions/foo"},
+		{in: "https://github.com/topics/go"},
+		{in: "https://github.com/sponsors/someone"},
+		{in: "https://github.com/notifications/beta"},
+		{in: "https://github.com/login/oauth"},
+		{in: "https://github.com/users/foo/projects"},
+		{in: "https://github.com/Settings/keys"},
+		{in: "settings/profile"},
+
+		{in: "https://gitlab.com/o/r", notGitHub: true},
+		{in: "gitlab.com/o/r", notGitHub: true},
+		{in: "git@gitlab.com:o/r.git", notGitHub: true},
+		{in: "https://github.com.evil.com/o/r", notGitHub: true},
+
+		// Local paths must not be mistaken for owner/repo shorthand.
+		{in: "/Users/me/repo", notGitHub: true},
+		{in: "/o/r", notGitHub: true},
+		{in: "~/gh/x", notGitHub: true},
+		{in: "~/r", notGitHub: true},
+		{in: "./repo", notGitHub: true},
+		{in: "../o/r", notGitHub: true},
+		{in: "src/main.go", notGitHub: true},
+		{in: "docs/readme.md", notGitHub: true},
+		{in: "internal/urlparse/urlparse.go", notGitHub: true},
+		{in: "-o/r", notGitHub: true},
+		{in: "o_x/r", notGitHub: true},
+		{in: "o/r/wiki", notGitHub: true},
+		{in: "o/r$/x", notGitHub: true},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", tt.in)
+			continue
+		}
+		if got := errors.Is(err, ErrNotGitHub); got != tt.notGitHub {
+			t.Errorf("Parse(%q) error %v: errors.Is(ErrNotGitHub) = %v, want %v", tt.in, err, got, tt.notGitHub)
+		}
+	}
+}
+
+func TestURLRoundTrip(t *testing.T) {
+	tests := []struct {
+		in  string
+		web string
+	}{
+		{"git@github.com:o/r.git", "https://github.com/o/r"},
+		{"https://github.com/o/r/tree/main/a/b", "https://github.com/o/r/tree/main/a/b"},
+		{"https://github.com/o/r/blob/main/x.go", "https://github.com/o/r/blob/main/x.go"},
+		{"https://github.com/o/r/pull/12/files", "https://github.com/o/r/pull/12"},
+		{"https://github.com/o/r/issues/7", "https://github.com/o/r/issues/7"},
+		{"https://github.com/o/r/commit/abc123", "https://github.com/o/r/commit/abc123"},
+		{"https://github.com/o/r/releases", "https://github.com/o/r/releases"},
+		{"https://github.com/o/r/releases/tag/v1.2.3", "https://github.com/o/r/releases/tag/v1.2.3"},
+		{"https://github.com/o/r/issues/new", "https://github.com/o/r"},
+
+		// Decoded paths are escaped again on the way out.
+		{"https://github.com/o/r/blob/main/a%20b.go", "https://github.com/o/r/blob/main/a%20b.go"},
+		{"https://github.com/o/r/tree/main/dir%20one/c%23.md", "https://github.com/o/r/tree/main/dir%20one/c%23.md"},
+		{"https://github.com/o/r/tree/feat%3Fx/a", "https://github.com/o/r/tree/feat%3Fx/a"},
+		{"https://github.com/o/r/releases/tag/v1%20beta", "https://github.com/o/r/releases/tag/v1%20beta"},
+	}
+	for _, tt := range tests {
+		u, err := Parse(tt.in)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.in, err)
+		}
+		if got := u.WebURL(); got != tt.web {
+			t.Errorf("Parse(%q).WebURL() = %q, want %q", tt.in, got, tt.web)
+		}
+		if got := u.FullName(); got != "o/r" {
+			t.Errorf("Parse(%q).FullName() = %q, want %q", tt.in, got, "o/r")
+		}
+		if got, want := u.CloneURL(), "https://github.com/o/r.git"; got != want {
+			t.Errorf("Parse(%q).CloneURL() = %q, want %q", tt.in, got, want)
+		}
+		if got, want := u.SSHURL(), "git@github.com:o/r.git"; got != want {
+			t.Errorf("Parse(%q).SSHURL() = %q, want %q", tt.in, got, want)
+		}
+
+		for _, s := range []string{u.WebURL(), u.CloneURL(), u.SSHURL()} {
+			again, err := Parse(s)
+			if err != nil {
+				t.Errorf("Parse(%q) error: %v", s, err)
+				continue
+			}
+			if again.FullName() != u.FullName() {
+				t.Errorf("Parse(%q).FullName() = %q, want %q", s, again.FullName(), u.FullName())
+			}
+		}
+		if again, _ := Parse(u.WebURL()); again != u {
+			t.Errorf("Parse(%q) = %+v, want %+v", u.WebURL(), again, u)
+		}
+	}
+}